@@ -0,0 +1,1071 @@
+package aws
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/terraform-providers/terraform-provider-aws/aws/internal/keyvaluetags"
+)
+
+func resourceAwsVpc() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsVpcCreate,
+		Read:   resourceAwsVpcRead,
+		Update: resourceAwsVpcUpdate,
+		Delete: resourceAwsVpcDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		CustomizeDiff: customdiff.All(
+			customdiff.ForceNewIfChange("instance_tenancy", func(old, new, meta interface{}) bool {
+				return old.(string) == ec2.TenancyDefault && new.(string) == ec2.TenancyDedicated
+			}),
+			resourceAwsVpcValidateSecondaryCidrBlocks,
+		),
+
+		Schema: map[string]*schema.Schema{
+			"cidr_block": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsCIDRNetwork(0, 32),
+			},
+
+			"instance_tenancy": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "default",
+				ValidateFunc: validation.StringInSlice([]string{
+					ec2.TenancyDefault,
+					ec2.TenancyDedicated,
+				}, false),
+			},
+
+			"enable_dns_hostnames": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"enable_dns_support": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"enable_classiclink": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+
+			"enable_classiclink_dns_support": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+
+			"assign_generated_ipv6_cidr_block": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"ipv6_ipam_pool_id", "ipv6_cidr_block", "ipv6_pool"},
+			},
+
+			"ipv6_ipam_pool_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"assign_generated_ipv6_cidr_block", "ipv6_pool"},
+			},
+
+			"ipv6_netmask_length": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"ipv6_cidr_block"},
+				RequiredWith:  []string{"ipv6_ipam_pool_id"},
+			},
+
+			"ipv6_cidr_block": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"assign_generated_ipv6_cidr_block", "ipv6_netmask_length"},
+				ValidateFunc:  validation.IsCIDRNetwork(0, 128),
+			},
+
+			"ipv6_pool": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"assign_generated_ipv6_cidr_block", "ipv6_ipam_pool_id"},
+			},
+
+			"arn": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"ipv6_association_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"owner_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"main_route_table_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"default_route_table_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"default_network_acl_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"default_security_group_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"dhcp_options_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"secondary_cidr_blocks": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateVpcSecondaryCidrBlock,
+				},
+			},
+
+			"tags": tagsSchema(),
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+func resourceAwsVpcCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2conn
+
+	input := &ec2.CreateVpcInput{
+		CidrBlock:                   aws.String(d.Get("cidr_block").(string)),
+		InstanceTenancy:             aws.String(d.Get("instance_tenancy").(string)),
+		AmazonProvidedIpv6CidrBlock: aws.Bool(d.Get("assign_generated_ipv6_cidr_block").(bool)),
+	}
+
+	if v, ok := d.GetOk("ipv6_ipam_pool_id"); ok {
+		input.Ipv6IpamPoolId = aws.String(v.(string))
+	}
+
+	if v, ok := d.GetOk("ipv6_netmask_length"); ok {
+		input.Ipv6NetmaskLength = aws.Int64(int64(v.(int)))
+	}
+
+	if v, ok := d.GetOk("ipv6_cidr_block"); ok {
+		input.Ipv6CidrBlock = aws.String(v.(string))
+	}
+
+	if v, ok := d.GetOk("ipv6_pool"); ok {
+		input.Ipv6Pool = aws.String(v.(string))
+	}
+
+	if v, ok := d.GetOk("tags"); ok {
+		input.TagSpecifications = ec2TagSpecificationsFromMap(v.(map[string]interface{}), ec2.ResourceTypeVpc)
+	}
+
+	log.Printf("[DEBUG] Creating VPC: %s", input)
+	resp, err := conn.CreateVpc(input)
+	if err != nil {
+		return fmt.Errorf("error creating VPC: %s", err)
+	}
+
+	d.SetId(aws.StringValue(resp.Vpc.VpcId))
+	log.Printf("[INFO] VPC ID: %s", d.Id())
+
+	if err := waitForEc2VpcStateAvailable(conn, d.Id()); err != nil {
+		return fmt.Errorf("error waiting for VPC (%s) to become available: %s", d.Id(), err)
+	}
+
+	if err := modifyVpcAttributesOnCreate(conn, d); err != nil {
+		return err
+	}
+
+	if v, ok := d.GetOk("secondary_cidr_blocks"); ok && v.(*schema.Set).Len() > 0 {
+		for _, vpcCIDRBlock := range v.(*schema.Set).List() {
+			if err := associateVpcSecondaryCidrBlock(conn, d.Id(), vpcCIDRBlock.(string)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return resourceAwsVpcRead(d, meta)
+}
+
+func resourceAwsVpcRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2conn
+	ignoreTagsConfig := meta.(*AWSClient).IgnoreTagsConfig
+
+	vpcRaw, _, err := VPCStateRefreshFunc(conn, d.Id())()
+	if err != nil {
+		return fmt.Errorf("error reading VPC (%s): %s", d.Id(), err)
+	}
+	if vpcRaw == nil {
+		log.Printf("[WARN] VPC (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	vpc := vpcRaw.(*ec2.Vpc)
+
+	vpcid := d.Id()
+	d.Set("cidr_block", vpc.CidrBlock)
+	d.Set("instance_tenancy", vpc.InstanceTenancy)
+	d.Set("owner_id", vpc.OwnerId)
+
+	d.Set("assign_generated_ipv6_cidr_block", false)
+	d.Set("ipv6_association_id", "")
+	d.Set("ipv6_cidr_block", "")
+	d.Set("ipv6_pool", "")
+	for _, a := range vpc.Ipv6CidrBlockAssociationSet {
+		if aws.StringValue(a.Ipv6CidrBlockState.State) == ec2.VpcCidrBlockStateCodeAssociated {
+			if pool := aws.StringValue(a.Ipv6Pool); pool == "" || pool == "Amazon" {
+				d.Set("assign_generated_ipv6_cidr_block", true)
+			} else {
+				d.Set("ipv6_pool", a.Ipv6Pool)
+			}
+			d.Set("ipv6_association_id", a.AssociationId)
+			d.Set("ipv6_cidr_block", a.Ipv6CidrBlock)
+			break
+		}
+	}
+
+	secondaryCidrBlocks := make([]string, 0, len(vpc.CidrBlockAssociationSet))
+	for _, a := range vpc.CidrBlockAssociationSet {
+		if aws.StringValue(a.CidrBlock) == aws.StringValue(vpc.CidrBlock) {
+			continue
+		}
+		if aws.StringValue(a.CidrBlockState.State) != ec2.VpcCidrBlockStateCodeAssociated {
+			continue
+		}
+		secondaryCidrBlocks = append(secondaryCidrBlocks, aws.StringValue(a.CidrBlock))
+	}
+	if err := d.Set("secondary_cidr_blocks", secondaryCidrBlocks); err != nil {
+		return fmt.Errorf("error setting secondary_cidr_blocks: %s", err)
+	}
+
+	vpcArn := arn.ARN{
+		Partition: meta.(*AWSClient).partition,
+		Service:   ec2.ServiceName,
+		Region:    meta.(*AWSClient).region,
+		AccountID: aws.StringValue(vpc.OwnerId),
+		Resource:  fmt.Sprintf("vpc/%s", d.Id()),
+	}.String()
+	d.Set("arn", vpcArn)
+
+	if err := resourceAwsVpcSetDefaultNetworkAcl(conn, d); err != nil {
+		log.Printf("[WARN] Unable to set Default Network ACL: %s", err)
+	}
+	if err := resourceAwsVpcSetDefaultSecurityGroup(conn, d); err != nil {
+		log.Printf("[WARN] Unable to set Default Security Group: %s", err)
+	}
+	if err := resourceAwsVpcSetDefaultRouteTable(conn, d); err != nil {
+		log.Printf("[WARN] Unable to set Default Route Table: %s", err)
+	}
+	if err := resourceAwsVpcSetMainRouteTable(conn, d); err != nil {
+		log.Printf("[WARN] Unable to set Main Route Table: %s", err)
+	}
+
+	if err := resourceAwsVpcSetDhcpOptionsId(conn, d); err != nil {
+		return err
+	}
+
+	desc, err := conn.DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+		Attribute: aws.String(ec2.VpcAttributeNameEnableDnsSupport),
+		VpcId:     aws.String(vpcid),
+	})
+	if err != nil {
+		return err
+	}
+	d.Set("enable_dns_support", desc.EnableDnsSupport.Value)
+
+	desc, err = conn.DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+		Attribute: aws.String(ec2.VpcAttributeNameEnableDnsHostnames),
+		VpcId:     aws.String(vpcid),
+	})
+	if err != nil {
+		return err
+	}
+	d.Set("enable_dns_hostnames", desc.EnableDnsHostnames.Value)
+
+	if err := resourceAwsVpcSetClassicLink(conn, d); err != nil {
+		log.Printf("[WARN] Unable to set Classiclink: %s", err)
+	}
+	if err := resourceAwsVpcSetClassicLinkDnsSupport(conn, d); err != nil {
+		log.Printf("[WARN] Unable to set Classiclink DNS Support: %s", err)
+	}
+
+	if err := d.Set("tags", keyvaluetags.Ec2KeyValueTags(vpc.Tags).IgnoreAws().IgnoreConfig(ignoreTagsConfig).Map()); err != nil {
+		return fmt.Errorf("error setting tags: %s", err)
+	}
+
+	return nil
+}
+
+func resourceAwsVpcUpdate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2conn
+
+	if d.HasChange("instance_tenancy") {
+		old, new := d.GetChange("instance_tenancy")
+		if old.(string) == ec2.TenancyDedicated && new.(string) == ec2.TenancyDefault {
+			if err := modifyVpcTenancy(conn, d.Id(), new.(string)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.HasChange("enable_dns_hostnames") {
+		if err := modifyVpcDnsHostnames(conn, d.Id(), d.Get("enable_dns_hostnames").(bool)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("enable_dns_support") {
+		if err := modifyVpcDnsSupport(conn, d.Id(), d.Get("enable_dns_support").(bool)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("enable_classiclink") {
+		if err := modifyVpcClassicLink(conn, d.Id(), d.Get("enable_classiclink").(bool)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("enable_classiclink_dns_support") {
+		if err := modifyVpcClassicLinkDnsSupport(conn, d.Id(), d.Get("enable_classiclink_dns_support").(bool)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChanges("assign_generated_ipv6_cidr_block", "ipv6_ipam_pool_id", "ipv6_netmask_length", "ipv6_cidr_block", "ipv6_pool") {
+		if err := modifyVpcIpv6CidrBlock(conn, d); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("dhcp_options_id") {
+		if err := modifyVpcDhcpOptions(conn, d); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("secondary_cidr_blocks") {
+		o, n := d.GetChange("secondary_cidr_blocks")
+		os := o.(*schema.Set)
+		ns := n.(*schema.Set)
+
+		for _, vpcCIDRBlock := range os.Difference(ns).List() {
+			if err := disassociateVpcSecondaryCidrBlock(conn, d.Id(), vpcCIDRBlock.(string)); err != nil {
+				return err
+			}
+		}
+
+		for _, vpcCIDRBlock := range ns.Difference(os).List() {
+			if err := associateVpcSecondaryCidrBlock(conn, d.Id(), vpcCIDRBlock.(string)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.HasChange("tags") && !d.IsNewResource() {
+		o, n := d.GetChange("tags")
+		if err := keyvaluetags.Ec2UpdateTags(conn, d.Id(), o, n); err != nil {
+			return fmt.Errorf("error updating VPC (%s) tags: %s", d.Id(), err)
+		}
+	}
+
+	return resourceAwsVpcRead(d, meta)
+}
+
+func resourceAwsVpcDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).ec2conn
+
+	vpcID := d.Id()
+	input := &ec2.DeleteVpcInput{
+		VpcId: aws.String(vpcID),
+	}
+
+	log.Printf("[INFO] Deleting VPC: %s", vpcID)
+
+	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err := conn.DeleteVpc(input)
+		if err == nil {
+			return nil
+		}
+
+		if isAWSErr(err, "InvalidVpcID.NotFound", "") {
+			return nil
+		}
+		if isAWSErr(err, "DependencyViolation", "") {
+			return resource.RetryableError(err)
+		}
+
+		return resource.NonRetryableError(err)
+	})
+
+	if isResourceTimeoutError(err) {
+		_, err = conn.DeleteVpc(input)
+		if isAWSErr(err, "InvalidVpcID.NotFound", "") {
+			return nil
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("error deleting VPC (%s): %s", vpcID, err)
+	}
+
+	return nil
+}
+
+func modifyVpcAttributesOnCreate(conn *ec2.EC2, d *schema.ResourceData) error {
+	if v := d.Get("enable_dns_hostnames").(bool); v {
+		if err := modifyVpcDnsHostnames(conn, d.Id(), v); err != nil {
+			return err
+		}
+	}
+
+	if !d.Get("enable_dns_support").(bool) {
+		if err := modifyVpcDnsSupport(conn, d.Id(), false); err != nil {
+			return err
+		}
+	}
+
+	if v, ok := d.GetOkExists("enable_classiclink"); ok {
+		if err := modifyVpcClassicLink(conn, d.Id(), v.(bool)); err != nil {
+			return err
+		}
+	}
+
+	if v, ok := d.GetOkExists("enable_classiclink_dns_support"); ok {
+		if err := modifyVpcClassicLinkDnsSupport(conn, d.Id(), v.(bool)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resourceAwsVpcValidateSecondaryCidrBlocks ensures secondary_cidr_blocks does
+// not overlap with the VPC's primary cidr_block.
+func resourceAwsVpcValidateSecondaryCidrBlocks(diff *schema.ResourceDiff, meta interface{}) error {
+	primary := diff.Get("cidr_block").(string)
+	if primary == "" {
+		return nil
+	}
+
+	_, primaryNet, err := net.ParseCIDR(primary)
+	if err != nil {
+		return nil
+	}
+
+	for _, v := range diff.Get("secondary_cidr_blocks").(*schema.Set).List() {
+		secondary := v.(string)
+
+		_, secondaryNet, err := net.ParseCIDR(secondary)
+		if err != nil {
+			continue
+		}
+
+		if primaryNet.Contains(secondaryNet.IP) || secondaryNet.Contains(primaryNet.IP) {
+			return fmt.Errorf("secondary_cidr_blocks entry %q overlaps with cidr_block %q", secondary, primary)
+		}
+	}
+
+	return nil
+}
+
+func validateVpcSecondaryCidrBlock(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if _, errs := validation.IsCIDRNetwork(0, 32)(v, k); len(errs) > 0 {
+		errors = append(errors, errs...)
+		return ws, errors
+	}
+
+	_, candidateNet, err := net.ParseCIDR(value)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a valid CIDR block: %s", k, err))
+		return ws, errors
+	}
+	candidateOnes, _ := candidateNet.Mask.Size()
+
+	private := false
+	for _, block := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"} {
+		_, privateNet, _ := net.ParseCIDR(block)
+		privateOnes, _ := privateNet.Mask.Size()
+
+		if privateNet.Contains(candidateNet.IP) && candidateOnes >= privateOnes {
+			private = true
+			break
+		}
+	}
+
+	if !private {
+		errors = append(errors, fmt.Errorf("%q (%s) must be within an RFC1918 private address range (10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16)", k, value))
+	}
+
+	return ws, errors
+}
+
+func modifyVpcTenancy(conn *ec2.EC2, vpcID string, tenancy string) error {
+	log.Printf("[INFO] Modifying instance tenancy for VPC (%s) to %s", vpcID, tenancy)
+	_, err := conn.ModifyVpcTenancy(&ec2.ModifyVpcTenancyInput{
+		VpcId:           aws.String(vpcID),
+		InstanceTenancy: aws.String(tenancy),
+	})
+	if err != nil {
+		return fmt.Errorf("error modifying instance tenancy for VPC (%s): %s", vpcID, err)
+	}
+	return nil
+}
+
+func modifyVpcDnsHostnames(conn *ec2.EC2, vpcID string, enabled bool) error {
+	log.Printf("[INFO] Modifying enableDnsHostnames VPC attribute for %s to %t", vpcID, enabled)
+	_, err := conn.ModifyVpcAttribute(&ec2.ModifyVpcAttributeInput{
+		VpcId:              aws.String(vpcID),
+		EnableDnsHostnames: &ec2.AttributeBooleanValue{Value: aws.Bool(enabled)},
+	})
+	if err != nil {
+		return fmt.Errorf("error modifying enableDnsHostnames VPC attribute: %s", err)
+	}
+	return nil
+}
+
+func modifyVpcDnsSupport(conn *ec2.EC2, vpcID string, enabled bool) error {
+	log.Printf("[INFO] Modifying enableDnsSupport VPC attribute for %s to %t", vpcID, enabled)
+	_, err := conn.ModifyVpcAttribute(&ec2.ModifyVpcAttributeInput{
+		VpcId:            aws.String(vpcID),
+		EnableDnsSupport: &ec2.AttributeBooleanValue{Value: aws.Bool(enabled)},
+	})
+	if err != nil {
+		return fmt.Errorf("error modifying enableDnsSupport VPC attribute: %s", err)
+	}
+	return nil
+}
+
+func modifyVpcClassicLink(conn *ec2.EC2, vpcID string, enabled bool) error {
+	if enabled {
+		log.Printf("[INFO] Enabling ClassicLink for VPC (%s)", vpcID)
+		_, err := conn.EnableVpcClassicLink(&ec2.EnableVpcClassicLinkInput{
+			VpcId: aws.String(vpcID),
+		})
+		if err != nil {
+			return fmt.Errorf("error enabling ClassicLink for VPC (%s): %s", vpcID, err)
+		}
+		return nil
+	}
+
+	log.Printf("[INFO] Disabling ClassicLink for VPC (%s)", vpcID)
+	_, err := conn.DisableVpcClassicLink(&ec2.DisableVpcClassicLinkInput{
+		VpcId: aws.String(vpcID),
+	})
+	if err != nil {
+		return fmt.Errorf("error disabling ClassicLink for VPC (%s): %s", vpcID, err)
+	}
+	return nil
+}
+
+func modifyVpcClassicLinkDnsSupport(conn *ec2.EC2, vpcID string, enabled bool) error {
+	if enabled {
+		log.Printf("[INFO] Enabling ClassicLink DNS support for VPC (%s)", vpcID)
+		_, err := conn.EnableVpcClassicLinkDnsSupport(&ec2.EnableVpcClassicLinkDnsSupportInput{
+			VpcId: aws.String(vpcID),
+		})
+		if err != nil {
+			return fmt.Errorf("error enabling ClassicLink DNS support for VPC (%s): %s", vpcID, err)
+		}
+		return nil
+	}
+
+	log.Printf("[INFO] Disabling ClassicLink DNS support for VPC (%s)", vpcID)
+	_, err := conn.DisableVpcClassicLinkDnsSupport(&ec2.DisableVpcClassicLinkDnsSupportInput{
+		VpcId: aws.String(vpcID),
+	})
+	if err != nil {
+		return fmt.Errorf("error disabling ClassicLink DNS support for VPC (%s): %s", vpcID, err)
+	}
+	return nil
+}
+
+// modifyVpcIpv6CidrBlock reconciles the Amazon-provided, IPAM-allocated and
+// BYOIP-owned IPv6 CIDR block association on update; on create these arguments
+// are passed to CreateVpc instead. Only one of these sources may be associated
+// with a VPC at a time.
+func modifyVpcIpv6CidrBlock(conn *ec2.EC2, d *schema.ResourceData) error {
+	wantAssociation := d.Get("assign_generated_ipv6_cidr_block").(bool) ||
+		d.Get("ipv6_ipam_pool_id").(string) != "" ||
+		d.Get("ipv6_cidr_block").(string) != "" ||
+		d.Get("ipv6_pool").(string) != ""
+
+	if wantAssociation {
+		if existingAssociationID := d.Get("ipv6_association_id").(string); existingAssociationID != "" {
+			if _, err := conn.DisassociateVpcCidrBlock(&ec2.DisassociateVpcCidrBlockInput{
+				AssociationId: aws.String(existingAssociationID),
+			}); err != nil {
+				return fmt.Errorf("error disassociating EC2 VPC (%s) IPv6 CIDR block (%s): %s", d.Id(), existingAssociationID, err)
+			}
+
+			if err := waitForEc2VpcIpv6CidrBlockAssociationDeleted(conn, d.Id(), existingAssociationID); err != nil {
+				return fmt.Errorf("error waiting for EC2 VPC (%s) IPv6 CIDR block (%s) to become disassociated: %s", d.Id(), existingAssociationID, err)
+			}
+		}
+
+		input := &ec2.AssociateVpcCidrBlockInput{
+			VpcId: aws.String(d.Id()),
+		}
+
+		if v := d.Get("ipv6_ipam_pool_id").(string); v != "" {
+			input.Ipv6IpamPoolId = aws.String(v)
+		} else if v := d.Get("ipv6_pool").(string); v != "" {
+			input.Ipv6Pool = aws.String(v)
+			if cidr := d.Get("ipv6_cidr_block").(string); cidr != "" {
+				input.Ipv6CidrBlock = aws.String(cidr)
+			}
+		} else if v := d.Get("ipv6_cidr_block").(string); v != "" {
+			input.Ipv6CidrBlock = aws.String(v)
+		} else {
+			input.AmazonProvidedIpv6CidrBlock = aws.Bool(true)
+		}
+
+		if v, ok := d.GetOk("ipv6_netmask_length"); ok {
+			input.Ipv6NetmaskLength = aws.Int64(int64(v.(int)))
+		}
+
+		resp, err := conn.AssociateVpcCidrBlock(input)
+		if err != nil {
+			return fmt.Errorf("error associating EC2 VPC (%s) IPv6 CIDR block: %s", d.Id(), err)
+		}
+
+		associationID := aws.StringValue(resp.Ipv6CidrBlockAssociation.AssociationId)
+
+		if err := waitForEc2VpcIpv6CidrBlockAssociationAvailable(conn, d.Id(), associationID); err != nil {
+			return fmt.Errorf("error waiting for EC2 VPC (%s) IPv6 CIDR block (%s) to become associated: %s", d.Id(), associationID, err)
+		}
+	} else {
+		associationID := d.Get("ipv6_association_id").(string)
+		if associationID == "" {
+			return nil
+		}
+
+		input := &ec2.DisassociateVpcCidrBlockInput{
+			AssociationId: aws.String(associationID),
+		}
+
+		_, err := conn.DisassociateVpcCidrBlock(input)
+		if err != nil {
+			return fmt.Errorf("error disassociating EC2 VPC (%s) IPv6 CIDR block (%s): %s", d.Id(), associationID, err)
+		}
+
+		if err := waitForEc2VpcIpv6CidrBlockAssociationDeleted(conn, d.Id(), associationID); err != nil {
+			return fmt.Errorf("error waiting for EC2 VPC (%s) IPv6 CIDR block (%s) to become disassociated: %s", d.Id(), associationID, err)
+		}
+	}
+
+	return nil
+}
+
+func associateVpcSecondaryCidrBlock(conn *ec2.EC2, vpcID string, cidrBlock string) error {
+	log.Printf("[INFO] Associating secondary CIDR block (%s) with VPC (%s)", cidrBlock, vpcID)
+	input := &ec2.AssociateVpcCidrBlockInput{
+		CidrBlock: aws.String(cidrBlock),
+		VpcId:     aws.String(vpcID),
+	}
+
+	var resp *ec2.AssociateVpcCidrBlockOutput
+	err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+		var err error
+		resp, err = conn.AssociateVpcCidrBlock(input)
+		if isAWSErr(err, "InvalidVpcID.NotFound", "") {
+			return resource.RetryableError(err)
+		}
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	if isResourceTimeoutError(err) {
+		resp, err = conn.AssociateVpcCidrBlock(input)
+	}
+	if err != nil {
+		return fmt.Errorf("error associating EC2 VPC (%s) secondary CIDR block (%s): %s", vpcID, cidrBlock, err)
+	}
+
+	associationID := aws.StringValue(resp.CidrBlockAssociation.AssociationId)
+
+	if err := waitForEc2VpcCidrBlockAssociationAvailable(conn, vpcID, associationID); err != nil {
+		return fmt.Errorf("error waiting for EC2 VPC (%s) secondary CIDR block (%s) to become associated: %s", vpcID, associationID, err)
+	}
+
+	return nil
+}
+
+func disassociateVpcSecondaryCidrBlock(conn *ec2.EC2, vpcID string, cidrBlock string) error {
+	vpcRaw, _, err := VPCStateRefreshFunc(conn, vpcID)()
+	if err != nil {
+		return fmt.Errorf("error reading VPC (%s): %s", vpcID, err)
+	}
+	if vpcRaw == nil {
+		return nil
+	}
+
+	vpc := vpcRaw.(*ec2.Vpc)
+	var associationID string
+	for _, a := range vpc.CidrBlockAssociationSet {
+		if aws.StringValue(a.CidrBlock) == cidrBlock {
+			associationID = aws.StringValue(a.AssociationId)
+			break
+		}
+	}
+	if associationID == "" {
+		return nil
+	}
+
+	log.Printf("[INFO] Disassociating secondary CIDR block (%s) from VPC (%s)", cidrBlock, vpcID)
+	_, err = conn.DisassociateVpcCidrBlock(&ec2.DisassociateVpcCidrBlockInput{
+		AssociationId: aws.String(associationID),
+	})
+	if err != nil {
+		return fmt.Errorf("error disassociating EC2 VPC (%s) secondary CIDR block (%s): %s", vpcID, cidrBlock, err)
+	}
+
+	if err := waitForEc2VpcCidrBlockAssociationDeleted(conn, vpcID, associationID); err != nil {
+		return fmt.Errorf("error waiting for EC2 VPC (%s) secondary CIDR block (%s) to become disassociated: %s", vpcID, associationID, err)
+	}
+
+	return nil
+}
+
+func modifyVpcDhcpOptions(conn *ec2.EC2, d *schema.ResourceData) error {
+	options := "default"
+	if v, ok := d.GetOk("dhcp_options_id"); ok {
+		options = v.(string)
+	}
+
+	log.Printf("[INFO] Modifying DHCP Options Set on VPC (%s) to %s", d.Id(), options)
+	_, err := conn.AssociateDhcpOptions(&ec2.AssociateDhcpOptionsInput{
+		DhcpOptionsId: aws.String(options),
+		VpcId:         aws.String(d.Id()),
+	})
+	if err != nil {
+		return fmt.Errorf("error associating DHCP Options Set (%s) to VPC (%s): %s", options, d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceAwsVpcSetDhcpOptionsId(conn *ec2.EC2, d *schema.ResourceData) error {
+	resp, err := conn.DescribeVpcs(&ec2.DescribeVpcsInput{
+		VpcIds: []*string{aws.String(d.Id())},
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp.Vpcs) == 0 {
+		return nil
+	}
+
+	d.Set("dhcp_options_id", resp.Vpcs[0].DhcpOptionsId)
+	return nil
+}
+
+func resourceAwsVpcSetDefaultNetworkAcl(conn *ec2.EC2, d *schema.ResourceData) error {
+	filters := []*ec2.Filter{
+		{
+			Name:   aws.String("default"),
+			Values: []*string{aws.String("true")},
+		},
+		{
+			Name:   aws.String("vpc-id"),
+			Values: []*string{aws.String(d.Id())},
+		},
+	}
+	networkAclResp, err := conn.DescribeNetworkAcls(&ec2.DescribeNetworkAclsInput{
+		Filters: filters,
+	})
+	if err != nil {
+		return err
+	}
+
+	if networkAclResp.NetworkAcls == nil || len(networkAclResp.NetworkAcls) == 0 {
+		return nil
+	}
+
+	d.Set("default_network_acl_id", networkAclResp.NetworkAcls[0].NetworkAclId)
+	return nil
+}
+
+func resourceAwsVpcSetDefaultSecurityGroup(conn *ec2.EC2, d *schema.ResourceData) error {
+	filters := []*ec2.Filter{
+		{
+			Name:   aws.String("group-name"),
+			Values: []*string{aws.String("default")},
+		},
+		{
+			Name:   aws.String("vpc-id"),
+			Values: []*string{aws.String(d.Id())},
+		},
+	}
+	securityGroupResp, err := conn.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		Filters: filters,
+	})
+	if err != nil {
+		return err
+	}
+
+	if securityGroupResp.SecurityGroups == nil || len(securityGroupResp.SecurityGroups) == 0 {
+		return nil
+	}
+
+	d.Set("default_security_group_id", securityGroupResp.SecurityGroups[0].GroupId)
+	return nil
+}
+
+func resourceAwsVpcSetDefaultRouteTable(conn *ec2.EC2, d *schema.ResourceData) error {
+	filters := []*ec2.Filter{
+		{
+			Name:   aws.String("association.main"),
+			Values: []*string{aws.String("true")},
+		},
+		{
+			Name:   aws.String("vpc-id"),
+			Values: []*string{aws.String(d.Id())},
+		},
+	}
+	routeTableResp, err := conn.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+		Filters: filters,
+	})
+	if err != nil {
+		return err
+	}
+
+	if routeTableResp.RouteTables == nil || len(routeTableResp.RouteTables) == 0 {
+		return nil
+	}
+
+	d.Set("default_route_table_id", routeTableResp.RouteTables[0].RouteTableId)
+	return nil
+}
+
+func resourceAwsVpcSetMainRouteTable(conn *ec2.EC2, d *schema.ResourceData) error {
+	filters := []*ec2.Filter{
+		{
+			Name:   aws.String("association.main"),
+			Values: []*string{aws.String("true")},
+		},
+		{
+			Name:   aws.String("vpc-id"),
+			Values: []*string{aws.String(d.Id())},
+		},
+	}
+	routeTableResp, err := conn.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+		Filters: filters,
+	})
+	if err != nil {
+		return err
+	}
+
+	if routeTableResp.RouteTables == nil || len(routeTableResp.RouteTables) == 0 {
+		return nil
+	}
+
+	d.Set("main_route_table_id", routeTableResp.RouteTables[0].RouteTableId)
+	return nil
+}
+
+func resourceAwsVpcSetClassicLink(conn *ec2.EC2, d *schema.ResourceData) error {
+	resp, err := conn.DescribeVpcClassicLink(&ec2.DescribeVpcClassicLinkInput{
+		VpcIds: []*string{aws.String(d.Id())},
+	})
+	if err != nil {
+		if isAWSErr(err, "UnsupportedOperation", "") {
+			return nil
+		}
+		return err
+	}
+
+	classicLinkEnabled := false
+	for _, v := range resp.Vpcs {
+		if aws.StringValue(v.VpcId) == d.Id() {
+			classicLinkEnabled = aws.BoolValue(v.ClassicLinkEnabled)
+			break
+		}
+	}
+	d.Set("enable_classiclink", classicLinkEnabled)
+	return nil
+}
+
+func resourceAwsVpcSetClassicLinkDnsSupport(conn *ec2.EC2, d *schema.ResourceData) error {
+	resp, err := conn.DescribeVpcClassicLinkDnsSupport(&ec2.DescribeVpcClassicLinkDnsSupportInput{
+		VpcIds: []*string{aws.String(d.Id())},
+	})
+	if err != nil {
+		if isAWSErr(err, "UnsupportedOperation", "") || isAWSErr(err, "AuthFailure", "") {
+			return nil
+		}
+		return err
+	}
+
+	classicLinkDnsSupported := false
+	for _, v := range resp.Vpcs {
+		if aws.StringValue(v.VpcId) == d.Id() {
+			classicLinkDnsSupported = aws.BoolValue(v.ClassicLinkDnsSupported)
+			break
+		}
+	}
+	d.Set("enable_classiclink_dns_support", classicLinkDnsSupported)
+	return nil
+}
+
+func VPCStateRefreshFunc(conn *ec2.EC2, id string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		resp, err := conn.DescribeVpcs(&ec2.DescribeVpcsInput{
+			VpcIds: []*string{aws.String(id)},
+		})
+		if err != nil {
+			if isAWSErr(err, "InvalidVpcID.NotFound", "") {
+				return nil, "", nil
+			}
+			return nil, "", err
+		}
+
+		if resp == nil || len(resp.Vpcs) == 0 || resp.Vpcs[0] == nil {
+			return nil, "", nil
+		}
+
+		vpc := resp.Vpcs[0]
+		return vpc, aws.StringValue(vpc.State), nil
+	}
+}
+
+func waitForEc2VpcStateAvailable(conn *ec2.EC2, id string) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{ec2.VpcStatePending},
+		Target:  []string{ec2.VpcStateAvailable},
+		Refresh: VPCStateRefreshFunc(conn, id),
+		Timeout: 10 * time.Minute,
+	}
+	_, err := stateConf.WaitForState()
+	return err
+}
+
+func waitForEc2VpcIpv6CidrBlockAssociationAvailable(conn *ec2.EC2, vpcID string, associationID string) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{ec2.VpcCidrBlockStateCodeAssociating, ec2.VpcCidrBlockStateCodeDisassociated},
+		Target:  []string{ec2.VpcCidrBlockStateCodeAssociated},
+		Refresh: ec2VpcIpv6CidrBlockAssociationStateRefresh(conn, vpcID, associationID),
+		Timeout: 10 * time.Minute,
+	}
+	_, err := stateConf.WaitForState()
+	return err
+}
+
+func waitForEc2VpcIpv6CidrBlockAssociationDeleted(conn *ec2.EC2, vpcID string, associationID string) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{ec2.VpcCidrBlockStateCodeAssociated, ec2.VpcCidrBlockStateCodeDisassociating},
+		Target:  []string{ec2.VpcCidrBlockStateCodeDisassociated},
+		Refresh: ec2VpcIpv6CidrBlockAssociationStateRefresh(conn, vpcID, associationID),
+		Timeout: 10 * time.Minute,
+	}
+	_, err := stateConf.WaitForState()
+	return err
+}
+
+func ec2VpcIpv6CidrBlockAssociationStateRefresh(conn *ec2.EC2, vpcID string, associationID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		vpcRaw, _, err := VPCStateRefreshFunc(conn, vpcID)()
+		if err != nil {
+			return nil, "", err
+		}
+		if vpcRaw == nil {
+			return nil, ec2.VpcCidrBlockStateCodeDisassociated, nil
+		}
+
+		vpc := vpcRaw.(*ec2.Vpc)
+		for _, a := range vpc.Ipv6CidrBlockAssociationSet {
+			if aws.StringValue(a.AssociationId) == associationID {
+				return a, aws.StringValue(a.Ipv6CidrBlockState.State), nil
+			}
+		}
+
+		return "", ec2.VpcCidrBlockStateCodeDisassociated, nil
+	}
+}
+
+func waitForEc2VpcCidrBlockAssociationAvailable(conn *ec2.EC2, vpcID string, associationID string) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{ec2.VpcCidrBlockStateCodeAssociating, ec2.VpcCidrBlockStateCodeDisassociated},
+		Target:  []string{ec2.VpcCidrBlockStateCodeAssociated},
+		Refresh: ec2VpcCidrBlockAssociationStateRefresh(conn, vpcID, associationID),
+		Timeout: 10 * time.Minute,
+	}
+	_, err := stateConf.WaitForState()
+	return err
+}
+
+func waitForEc2VpcCidrBlockAssociationDeleted(conn *ec2.EC2, vpcID string, associationID string) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{ec2.VpcCidrBlockStateCodeAssociated, ec2.VpcCidrBlockStateCodeDisassociating},
+		Target:  []string{ec2.VpcCidrBlockStateCodeDisassociated},
+		Refresh: ec2VpcCidrBlockAssociationStateRefresh(conn, vpcID, associationID),
+		Timeout: 10 * time.Minute,
+	}
+	_, err := stateConf.WaitForState()
+	return err
+}
+
+func ec2VpcCidrBlockAssociationStateRefresh(conn *ec2.EC2, vpcID string, associationID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		vpcRaw, _, err := VPCStateRefreshFunc(conn, vpcID)()
+		if err != nil {
+			return nil, "", err
+		}
+		if vpcRaw == nil {
+			return nil, ec2.VpcCidrBlockStateCodeDisassociated, nil
+		}
+
+		vpc := vpcRaw.(*ec2.Vpc)
+		for _, a := range vpc.CidrBlockAssociationSet {
+			if aws.StringValue(a.AssociationId) == associationID {
+				return a, aws.StringValue(a.CidrBlockState.State), nil
+			}
+		}
+
+		return "", ec2.VpcCidrBlockStateCodeDisassociated, nil
+	}
+}
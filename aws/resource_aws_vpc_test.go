@@ -3,7 +3,9 @@ package aws
 import (
 	"fmt"
 	"log"
+	"os"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
@@ -248,6 +251,56 @@ func TestAccAWSVpc_AssignGeneratedIpv6CidrBlock(t *testing.T) {
 	})
 }
 
+func TestAccAWSVpc_Ipv6CidrBlock_BYOIP(t *testing.T) {
+	resourceName := "aws_vpc.test"
+	poolID := os.Getenv("AWS_IPV6_BYOIP_POOL_ID")
+	if poolID == "" {
+		t.Skip("AWS_IPV6_BYOIP_POOL_ID environment variable not set")
+	}
+
+	var vpc ec2.Vpc
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVpcDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVpcConfigIpv6CidrBlockByoipPool(poolID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVpcExists(resourceName, &vpc),
+					resource.TestCheckResourceAttr(resourceName, "ipv6_pool", poolID),
+					resource.TestMatchResourceAttr(resourceName, "ipv6_association_id", regexp.MustCompile(`^vpc-cidr-assoc-.+`)),
+					resource.TestMatchResourceAttr(resourceName, "ipv6_cidr_block", regexp.MustCompile(`/56$`)),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					// This is needed because we don't always call d.Set() in Read for tags as per
+					// https://github.com/hashicorp/terraform/pull/21019 and https://github.com/hashicorp/terraform/issues/20985
+					"tags",
+				},
+			},
+		},
+	})
+}
+
+func testAccVpcConfigIpv6CidrBlockByoipPool(poolID string) string {
+	return fmt.Sprintf(`
+resource "aws_vpc" "test" {
+  cidr_block = "10.1.0.0/16"
+  ipv6_pool  = %[1]q
+
+  tags = {
+    Name = "terraform-testacc-vpc-ipv6-byoip"
+  }
+}
+`, poolID)
+}
+
 func TestAccAWSVpc_Tenancy(t *testing.T) {
 	var vpcDedicated ec2.Vpc
 	var vpcDefault ec2.Vpc
@@ -366,6 +419,128 @@ func TestAccAWSVpc_update(t *testing.T) {
 	})
 }
 
+func TestAccAWSVpc_SecondaryCidrBlocks(t *testing.T) {
+	var vpc ec2.Vpc
+	resourceName := "aws_vpc.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVpcDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVpcConfigSecondaryCidrBlocks("10.2.0.0/16", "10.3.0.0/16"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVpcExists(resourceName, &vpc),
+					resource.TestCheckResourceAttr(resourceName, "secondary_cidr_blocks.#", "2"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "secondary_cidr_blocks.*", "10.2.0.0/16"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "secondary_cidr_blocks.*", "10.3.0.0/16"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					// This is needed because we don't always call d.Set() in Read for tags as per
+					// https://github.com/hashicorp/terraform/pull/21019 and https://github.com/hashicorp/terraform/issues/20985
+					"tags",
+				},
+			},
+			{
+				Config: testAccVpcConfigSecondaryCidrBlocks("10.3.0.0/16"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVpcExists(resourceName, &vpc),
+					resource.TestCheckResourceAttr(resourceName, "secondary_cidr_blocks.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "secondary_cidr_blocks.*", "10.3.0.0/16"),
+				),
+			},
+			{
+				Config: testAccVpcConfigSecondaryCidrBlocks("10.4.0.0/16"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVpcExists(resourceName, &vpc),
+					resource.TestCheckResourceAttr(resourceName, "secondary_cidr_blocks.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "secondary_cidr_blocks.*", "10.4.0.0/16"),
+				),
+			},
+			{
+				Config: testAccVpcConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVpcExists(resourceName, &vpc),
+					resource.TestCheckResourceAttr(resourceName, "secondary_cidr_blocks.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccVpcConfigSecondaryCidrBlocks(cidrBlocks ...string) string {
+	quoted := make([]string, len(cidrBlocks))
+	for i, c := range cidrBlocks {
+		quoted[i] = fmt.Sprintf("%q", c)
+	}
+
+	return fmt.Sprintf(`
+resource "aws_vpc" "test" {
+  cidr_block            = "10.1.0.0/16"
+  secondary_cidr_blocks = [%s]
+
+  tags = {
+    Name = "terraform-testacc-vpc-secondary-cidr-blocks"
+  }
+}
+`, strings.Join(quoted, ", "))
+}
+
+func TestAccDataSourceAwsVpcs_basic(t *testing.T) {
+	rInt := acctest.RandInt()
+	resourceName := "data.aws_vpcs.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVpcDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAwsVpcsConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "ids.#", "2"),
+					resource.TestCheckTypeSetElemAttrPair(resourceName, "ids.*", "aws_vpc.test1", "id"),
+					resource.TestCheckTypeSetElemAttrPair(resourceName, "ids.*", "aws_vpc.test2", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAwsVpcsConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "aws_vpc" "test1" {
+	cidr_block = "10.1.0.0/16"
+	tags = {
+		Name = "terraform-testacc-vpcs-data-source-%[1]d"
+		TestAccAWSVpcsDataSource = "static"
+	}
+}
+
+resource "aws_vpc" "test2" {
+	cidr_block = "10.2.0.0/16"
+	tags = {
+		Name = "terraform-testacc-vpcs-data-source-%[1]d-other"
+		TestAccAWSVpcsDataSource = "static"
+	}
+}
+
+data "aws_vpcs" "test" {
+	tags = {
+		TestAccAWSVpcsDataSource = "static"
+	}
+
+	depends_on = [aws_vpc.test1, aws_vpc.test2]
+}
+`, rInt)
+}
+
 func testAccCheckVpcDestroy(s *terraform.State) error {
 	conn := testAccProvider.Meta().(*AWSClient).ec2conn
 